@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// backtraceSet holds the file:line locations registered by SetBacktraceAt,
+// keyed by file basename. A line of -1 means the entry was registered as
+// "file:*" and should dump every goroutine's stack, not just the current
+// one.
+var (
+	backtraceMu  sync.RWMutex
+	backtraceSet map[string]map[int]bool
+)
+
+// SetBacktraceAt takes a glog-style comma-separated list of "file:line"
+// locations; whenever a log call's caller matches one of them, the logger
+// additionally emits a runtime.Stack dump alongside the message. A
+// location ending in ":*" instead of a line number dumps every goroutine's
+// stack rather than just the caller's.
+func SetBacktraceAt(spec string) {
+	set := make(map[string]map[int]bool)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		file := filepath.Base(parts[0])
+		if set[file] == nil {
+			set[file] = make(map[int]bool)
+		}
+		if parts[1] == "*" {
+			set[file][-1] = true
+			continue
+		}
+		if n, err := strconv.Atoi(parts[1]); err == nil {
+			set[file][n] = true
+		}
+	}
+	backtraceMu.Lock()
+	backtraceSet = set
+	backtraceMu.Unlock()
+}
+
+func backtraceEnabled() bool {
+	backtraceMu.RLock()
+	defer backtraceMu.RUnlock()
+	return len(backtraceSet) > 0
+}
+
+// backtraceMatch reports whether file:line was registered via
+// SetBacktraceAt, and if so whether the match requests every goroutine's
+// stack (true) or just the caller's (false).
+func backtraceMatch(file string, line int) (matched, allGoroutines bool) {
+	backtraceMu.RLock()
+	defer backtraceMu.RUnlock()
+	lines, ok := backtraceSet[filepath.Base(file)]
+	if !ok {
+		return false, false
+	}
+	if lines[-1] {
+		return true, true
+	}
+	return lines[line], false
+}
+
+// captureStack returns the current goroutine's stack, or every goroutine's
+// stack when all is true, growing the scratch buffer until the dump fits.
+func captureStack(all bool) string {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, all)
+		if n < len(buf) {
+			return string(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
@@ -0,0 +1,22 @@
+package logger
+
+import "io"
+
+// fder is implemented by *os.File (and anything else exposing a raw
+// descriptor); it's how isTerminalWriter recognizes a writer that might be
+// a console/tty rather than a plain file or buffer.
+type fder interface {
+	Fd() uintptr
+}
+
+// isTerminalWriter reports whether w is connected to a terminal. It is
+// used by NewLogger to auto-enable TextFormatter's colorized output. The
+// actual per-descriptor check (isTerminal) is platform-specific: see
+// color_unix.go and color_windows.go.
+func isTerminalWriter(w io.Writer) bool {
+	f, ok := w.(fder)
+	if !ok {
+		return false
+	}
+	return isTerminal(f.Fd())
+}
@@ -0,0 +1,6 @@
+//go:build darwin || freebsd || netbsd || openbsd
+
+package logger
+
+// TIOCGETA, as used by libc's isatty() on the BSDs (including Darwin).
+const ioctlGetTermios = 0x40487413
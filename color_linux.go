@@ -0,0 +1,6 @@
+//go:build linux
+
+package logger
+
+// TCGETS, as used by glibc's isatty() on Linux.
+const ioctlGetTermios = 0x5401
@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestIsTerminalWriterNonFder(t *testing.T) {
+	var buf bytes.Buffer
+	if isTerminalWriter(&buf) {
+		t.Fatalf("a bytes.Buffer has no Fd(), should never be treated as a terminal")
+	}
+}
+
+func TestUseColorPrecedence(t *testing.T) {
+	cases := []struct {
+		name          string
+		f             TextFormatter
+		noColor       string
+		clicolorForce string
+		want          bool
+	}{
+		{name: "auto-detected off", f: TextFormatter{color: false}, want: false},
+		{name: "auto-detected on", f: TextFormatter{color: true}, want: true},
+		{name: "DisableColors wins over everything", f: TextFormatter{color: true, ForceColors: true, DisableColors: true}, clicolorForce: "1", want: false},
+		{name: "NO_COLOR overrides auto-detected on", f: TextFormatter{color: true}, noColor: "1", want: false},
+		{name: "ForceColors overrides auto-detected off", f: TextFormatter{color: false, ForceColors: true}, want: true},
+		{name: "CLICOLOR_FORCE overrides auto-detected off", f: TextFormatter{color: false}, clicolorForce: "1", want: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", c.noColor)
+			t.Setenv("CLICOLOR_FORCE", c.clicolorForce)
+			f := c.f
+			if got := f.useColor(); got != c.want {
+				t.Errorf("useColor() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package logger
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// isTerminal reports whether fd refers to a terminal, via the same
+// TCGETS/TIOCGETA ioctl every isatty(3) implementation uses. ioctlGetTermios
+// is the OS-specific request number; see color_linux.go / color_bsd.go.
+func isTerminal(fd uintptr) bool {
+	var termios syscall.Termios
+	_, _, errno := syscall.Syscall6(syscall.SYS_IOCTL, fd, ioctlGetTermios, uintptr(unsafe.Pointer(&termios)), 0, 0, 0)
+	return errno == 0
+}
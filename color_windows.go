@@ -0,0 +1,31 @@
+//go:build windows
+
+package logger
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32           = syscall.NewLazyDLL("kernel32.dll")
+	procGetConsoleMode = kernel32.NewProc("GetConsoleMode")
+	procSetConsoleMode = kernel32.NewProc("SetConsoleMode")
+)
+
+const enableVirtualTerminalProcessing = 0x0004
+
+// isTerminal reports whether fd is a Windows console, and if so also
+// enables ENABLE_VIRTUAL_TERMINAL_PROCESSING on it (the approach
+// popularized by go-windows-terminal-sequences / logrus) so that the ANSI
+// SGR codes TextFormatter emits render instead of leaking through as
+// literal escape sequences on Windows 10+.
+func isTerminal(fd uintptr) bool {
+	var mode uint32
+	r, _, _ := procGetConsoleMode.Call(fd, uintptr(unsafe.Pointer(&mode)))
+	if r == 0 {
+		return false
+	}
+	procSetConsoleMode.Call(fd, uintptr(mode|enableVirtualTerminalProcessing))
+	return true
+}
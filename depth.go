@@ -0,0 +1,63 @@
+package logger
+
+import "fmt"
+
+// The *Depthf variants let a wrapper around this package report the file
+// and line of its own caller rather than its own, by passing a non-zero
+// depth. depth is the number of stack frames to skip above the direct
+// caller of e.g. InfoDepthf: 0 attributes that direct caller (as Info
+// does), 1 attributes its caller, and so on.
+
+func (l *Logger) InfoDepthf(depth int, format string, v ...interface{}) {
+	if l.upto&LOG_MASK(LOG_INFO) != 0 {
+		l.output(2+depth, LOG_INFO, fmt.Sprintf(format, v...))
+	}
+}
+func (l *Logger) DebugDepthf(depth int, format string, v ...interface{}) {
+	if l.upto&LOG_MASK(LOG_DEBUG) != 0 {
+		l.output(2+depth, LOG_DEBUG, fmt.Sprintf(format, v...))
+	}
+}
+func (l *Logger) WarningDepthf(depth int, format string, v ...interface{}) {
+	if l.upto&LOG_MASK(LOG_WARNING) != 0 {
+		l.output(2+depth, LOG_WARNING, fmt.Sprintf(format, v...))
+	}
+}
+func (l *Logger) ErrorDepthf(depth int, format string, v ...interface{}) {
+	if l.upto&LOG_MASK(LOG_ERR) != 0 {
+		l.output(2+depth, LOG_ERR, fmt.Sprintf(format, v...))
+	}
+}
+func (l *Logger) CritDepthf(depth int, format string, v ...interface{}) {
+	if l.upto&LOG_MASK(LOG_CRIT) != 0 {
+		l.output(2+depth, LOG_CRIT, fmt.Sprintf(format, v...))
+	}
+}
+func (l *Logger) FatalDepthf(depth int, format string, v ...interface{}) {
+	l.fatal(3+depth, format, v...)
+}
+func (l *Logger) PanicDepthf(depth int, format string, v ...interface{}) {
+	l.panic(3+depth, format, v...)
+}
+
+func InfoDepthf(depth int, format string, v ...interface{}) {
+	std.InfoDepthf(depth+1, format, v...)
+}
+func DebugDepthf(depth int, format string, v ...interface{}) {
+	std.DebugDepthf(depth+1, format, v...)
+}
+func WarningDepthf(depth int, format string, v ...interface{}) {
+	std.WarningDepthf(depth+1, format, v...)
+}
+func ErrorDepthf(depth int, format string, v ...interface{}) {
+	std.ErrorDepthf(depth+1, format, v...)
+}
+func CritDepthf(depth int, format string, v ...interface{}) {
+	std.CritDepthf(depth+1, format, v...)
+}
+func FatalDepthf(depth int, format string, v ...interface{}) {
+	std.FatalDepthf(depth+1, format, v...)
+}
+func PanicDepthf(depth int, format string, v ...interface{}) {
+	std.PanicDepthf(depth+1, format, v...)
+}
@@ -0,0 +1,201 @@
+package logger
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// wrapInfoDepthf mimics a thin logging wrapper: its own caller, not
+// wrapInfoDepthf itself, should be attributed as the source of the message.
+func wrapInfoDepthf(l *Logger, format string, v ...interface{}) {
+	l.InfoDepthf(1, format, v...)
+}
+
+func TestInfoDepthfAttributesCaller(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(&buf, "", log.Lshortfile, LOG_DEBUG)
+
+	wrapInfoDepthf(l, "hello") // the caller attributed should be this line
+
+	got := buf.String()
+	if !strings.Contains(got, "depth_test.go:") {
+		t.Fatalf("expected caller attributed to depth_test.go, got %q", got)
+	}
+	if strings.Contains(got, "depth.go:") {
+		t.Fatalf("expected caller attribution to skip past the Depthf wrapper itself, got %q", got)
+	}
+}
+
+func TestInfoAttributesDirectCaller(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(&buf, "", log.Lshortfile, LOG_DEBUG)
+
+	l.Info("hello") // a direct call, not via a wrapper
+
+	got := buf.String()
+	if !strings.Contains(got, "depth_test.go:") {
+		t.Fatalf("expected caller attributed to depth_test.go, got %q", got)
+	}
+}
+
+// TestInstanceDepthfVariantsAttributeCaller exercises every *Depthf variant
+// besides InfoDepthf (covered above), each through a one-frame wrapper, the
+// same shape a real logging wrapper around this package would use.
+func TestInstanceDepthfVariantsAttributeCaller(t *testing.T) {
+	wrappers := map[string]func(l *Logger, format string, v ...interface{}){
+		"DebugDepthf":   func(l *Logger, format string, v ...interface{}) { l.DebugDepthf(1, format, v...) },
+		"WarningDepthf": func(l *Logger, format string, v ...interface{}) { l.WarningDepthf(1, format, v...) },
+		"ErrorDepthf":   func(l *Logger, format string, v ...interface{}) { l.ErrorDepthf(1, format, v...) },
+		"CritDepthf":    func(l *Logger, format string, v ...interface{}) { l.CritDepthf(1, format, v...) },
+	}
+	for name, wrap := range wrappers {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := NewLogger(&buf, "", log.Lshortfile, LOG_DEBUG)
+
+			wrap(l, "hello") // the caller attributed should be this line
+
+			got := buf.String()
+			if !strings.Contains(got, "depth_test.go:") {
+				t.Fatalf("%s: expected caller attributed to depth_test.go, got %q", name, got)
+			}
+			if strings.Contains(got, "depth.go:") {
+				t.Fatalf("%s: expected caller attribution to skip past the wrapper itself, got %q", name, got)
+			}
+		})
+	}
+}
+
+// wrapPkgErrorDepthf mimics a package-level logging wrapper built on top of
+// this package's own package-level wrappers, which apply an extra +1 depth
+// adjustment (see depth.go) on top of the instance-level methods.
+func wrapPkgErrorDepthf(format string, v ...interface{}) {
+	ErrorDepthf(1, format, v...)
+}
+
+func TestPackageLevelDepthfAttributesCaller(t *testing.T) {
+	oldOut, oldFlag, oldPriority := std.out, std.Flags(), std.Priority()
+	defer func() {
+		SetOutput(oldOut)
+		SetFlags(oldFlag)
+		SetPriority(oldPriority)
+	}()
+
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	SetFlags(log.Lshortfile)
+	SetPriority(LOG_DEBUG)
+
+	wrapPkgErrorDepthf("hello") // the caller attributed should be this line
+
+	got := buf.String()
+	if !strings.Contains(got, "depth_test.go:") {
+		t.Fatalf("expected caller attributed to depth_test.go, got %q", got)
+	}
+	if strings.Contains(got, "depth.go:") {
+		t.Fatalf("expected caller attribution to skip past both wrapper layers, got %q", got)
+	}
+}
+
+// TestPanicDepthfCalldepth checks PanicDepthf's calldepth arithmetic the
+// same way SetBacktraceAt/backtraceMatch do: registering a wildcard
+// "file:*" match on this test file and confirming the backtrace dump
+// fires proves runtime.Caller(calldepth) resolved to a frame in this file
+// rather than to PanicDepthf or l.panic itself (depth.go / logger.go),
+// where backtraceMatch would report no match. wrapPanicDepthf is in this
+// file too, so a calldepth one frame short of the wrapper's caller would
+// also pass.
+func wrapPanicDepthf(l *Logger) {
+	l.PanicDepthf(1, "boom")
+}
+
+func TestPanicDepthfCalldepth(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(&buf, "", 0, LOG_DEBUG)
+
+	SetBacktraceAt("depth_test.go:*")
+	defer SetBacktraceAt("")
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatalf("expected PanicDepthf to panic")
+		}
+		if !strings.Contains(buf.String(), "goroutine") {
+			t.Fatalf("expected a backtrace dump proving calldepth resolved into depth_test.go, got %q", buf.String())
+		}
+	}()
+	wrapPanicDepthf(l)
+}
+
+// TestFatalDepthfCalldepth checks FatalDepthf's calldepth arithmetic the
+// same way: os.Exit makes it impossible to assert on in-process, so the
+// real call happens in a re-exec'd helper process and the parent inspects
+// its output.
+func TestFatalDepthfCalldepth(t *testing.T) {
+	if os.Getenv("GOLOGGER_FATAL_DEPTHF_HELPER") == "1" {
+		fatalDepthfHelper()
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=TestFatalDepthfCalldepth")
+	cmd.Env = append(os.Environ(), "GOLOGGER_FATAL_DEPTHF_HELPER=1")
+	out, err := cmd.CombinedOutput()
+
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		t.Fatalf("expected the helper process to exit via os.Exit(1), err=%v, out=%s", err, out)
+	}
+	if exitErr.ExitCode() != 1 {
+		t.Fatalf("expected exit code 1, got %d, out=%s", exitErr.ExitCode(), out)
+	}
+	if !strings.Contains(string(out), "goroutine") {
+		t.Fatalf("expected a backtrace dump proving FatalDepthf's calldepth resolved into depth_test.go, got %q", out)
+	}
+}
+
+func wrapFatalDepthf() {
+	FatalDepthf(1, "boom")
+}
+
+func fatalDepthfHelper() {
+	SetOutput(os.Stdout)
+	SetBacktraceAt("depth_test.go:*")
+	wrapFatalDepthf()
+}
+
+func TestSetBacktraceAtParsing(t *testing.T) {
+	SetBacktraceAt("foo.go:10,bar.go:*")
+	defer SetBacktraceAt("")
+
+	if !backtraceEnabled() {
+		t.Fatalf("expected backtraceEnabled() after SetBacktraceAt")
+	}
+
+	matched, all := backtraceMatch("/path/to/foo.go", 10)
+	if !matched || all {
+		t.Fatalf("expected foo.go:10 to match single-goroutine, got matched=%v all=%v", matched, all)
+	}
+
+	matched, _ = backtraceMatch("/path/to/foo.go", 11)
+	if matched {
+		t.Fatalf("foo.go:11 should not match")
+	}
+
+	matched, all = backtraceMatch("/elsewhere/bar.go", 9999)
+	if !matched || !all {
+		t.Fatalf("expected bar.go:* to match any line with all goroutines, got matched=%v all=%v", matched, all)
+	}
+}
+
+func TestSetBacktraceAtClear(t *testing.T) {
+	SetBacktraceAt("foo.go:10")
+	SetBacktraceAt("")
+	if backtraceEnabled() {
+		t.Fatalf("expected SetBacktraceAt(\"\") to disable backtrace matching")
+	}
+}
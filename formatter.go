@@ -0,0 +1,155 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// TextFormatter renders an Entry the way the original *log.Logger-backed
+// implementation did: an optional date/time prefix honoring the same bits
+// as the standard "log" package (Ldate, Ltime, Lmicroseconds, Lshortfile,
+// Llongfile), followed by "[level] message" and any bound fields appended
+// as "key=value" pairs. It is the default Formatter installed by
+// NewLogger.
+//
+// The "[level]" token is colorized with ANSI SGR codes when color is in
+// effect: auto-enabled when NewLogger's out is a terminal, overridden by
+// ForceColors/DisableColors or the NO_COLOR/CLICOLOR_FORCE environment
+// variables.
+type TextFormatter struct {
+	Flag          int
+	ForceColors   bool
+	DisableColors bool
+
+	color bool // auto-detected terminal state; set by NewLogger/SetOutput
+}
+
+// levelColors maps each Level to the ANSI SGR code its "[level]" token is
+// wrapped in when color is enabled.
+var levelColors = map[Level]int{
+	LOG_EMERG:   31, // red
+	LOG_ALERT:   31, // red
+	LOG_CRIT:    31, // red
+	LOG_ERR:     31, // red
+	LOG_WARNING: 33, // yellow
+	LOG_NOTICE:  36, // cyan
+	LOG_INFO:    36, // cyan
+	LOG_DEBUG:   90, // gray
+}
+
+func (f *TextFormatter) useColor() bool {
+	if f.DisableColors {
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if f.ForceColors || os.Getenv("CLICOLOR_FORCE") != "" {
+		return true
+	}
+	return f.color
+}
+
+func (f *TextFormatter) Format(entry *Entry) ([]byte, error) {
+	var buf bytes.Buffer
+	if f.Flag&(log.Ldate|log.Ltime|log.Lmicroseconds) != 0 {
+		if f.Flag&log.Ldate != 0 {
+			y, m, d := entry.Time.Date()
+			fmt.Fprintf(&buf, "%04d/%02d/%02d ", y, m, d)
+		}
+		if f.Flag&(log.Ltime|log.Lmicroseconds) != 0 {
+			h, m, s := entry.Time.Clock()
+			fmt.Fprintf(&buf, "%02d:%02d:%02d", h, m, s)
+			if f.Flag&log.Lmicroseconds != 0 {
+				fmt.Fprintf(&buf, ".%06d", entry.Time.Nanosecond()/1e3)
+			}
+			buf.WriteByte(' ')
+		}
+	}
+	if entry.Caller != "" {
+		fmt.Fprintf(&buf, "%s: ", entry.Caller)
+	}
+	levelToken := fmt.Sprintf("[%s]", Levels[entry.Level])
+	if f.useColor() {
+		levelToken = fmt.Sprintf("\x1b[%dm%s\x1b[0m", levelColors[entry.Level], levelToken)
+	}
+	fmt.Fprintf(&buf, "%s %s", levelToken, entry.Message)
+	for _, k := range sortedFieldKeys(entry.Fields) {
+		fmt.Fprintf(&buf, " %s=%v", k, entry.Fields[k])
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// sortedFieldKeys returns fields' keys in sorted order, so two Entries
+// with the same Fields always render identically instead of depending on
+// Go's randomized map iteration order.
+func sortedFieldKeys(fields Fields) []string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// JSONFormatter renders an Entry as a single-line JSON object, one per
+// call. The reserved "time"/"level"/"msg" keys win over a bound field of
+// the same name, as does "caller" whenever the Entry has a Caller.
+type JSONFormatter struct{}
+
+func (f *JSONFormatter) Format(entry *Entry) ([]byte, error) {
+	m := make(map[string]interface{}, len(entry.Fields)+4)
+	for k, v := range entry.Fields {
+		m[k] = v
+	}
+	m["time"] = entry.Time.Format(RFC3339Milli)
+	m["level"] = Levels[entry.Level]
+	m["msg"] = entry.Message
+	if entry.Caller != "" {
+		m["caller"] = entry.Caller
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// LogfmtFormatter renders an Entry as a logfmt line (time=... level=...
+// msg=... key=value ...), quoting any value that contains whitespace, an
+// "=" or a double quote.
+type LogfmtFormatter struct{}
+
+func (f *LogfmtFormatter) Format(entry *Entry) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "time=%s level=%s msg=%s",
+		entry.Time.Format(RFC3339Milli), Levels[entry.Level], logfmtValue(entry.Message))
+	if entry.Caller != "" {
+		fmt.Fprintf(&buf, " caller=%s", logfmtValue(entry.Caller))
+	}
+	for _, k := range sortedFieldKeys(entry.Fields) {
+		fmt.Fprintf(&buf, " %s=%s", k, logfmtValue(fmt.Sprintf("%v", entry.Fields[k])))
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+func logfmtValue(s string) string {
+	if strings.ContainsAny(s, "=\"") || strings.IndexFunc(s, unicode.IsSpace) >= 0 {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// RFC3339Milli is RFC3339 with millisecond precision, used by JSONFormatter
+// and LogfmtFormatter so timestamps stay reasonably precise without the
+// noise of full nanosecond output.
+const RFC3339Milli = "2006-01-02T15:04:05.000Z07:00"
@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTextFormatterFieldOrder(t *testing.T) {
+	f := &TextFormatter{}
+	entry := &Entry{
+		Level:   LOG_INFO,
+		Message: "hello",
+		Fields:  Fields{"zeta": 1, "alpha": 2, "mu": 3},
+	}
+	b, err := f.Format(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(b)
+	wantOrder := []string{"alpha=2", "mu=3", "zeta=1"}
+	last := -1
+	for _, want := range wantOrder {
+		idx := strings.Index(got, want)
+		if idx < 0 {
+			t.Fatalf("missing %q in %q", want, got)
+		}
+		if idx < last {
+			t.Fatalf("fields out of order, got %q", got)
+		}
+		last = idx
+	}
+}
+
+func TestTextFormatterDeterministic(t *testing.T) {
+	f := &TextFormatter{}
+	entry := &Entry{Level: LOG_INFO, Message: "hi", Fields: Fields{"b": 1, "a": 2, "c": 3}}
+	first, err := f.Format(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		b, err := f.Format(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != string(first) {
+			t.Fatalf("non-deterministic output: %q vs %q", first, b)
+		}
+	}
+}
+
+func TestTextFormatterColor(t *testing.T) {
+	f := &TextFormatter{ForceColors: true}
+	entry := &Entry{Level: LOG_ERR, Message: "boom"}
+	b, err := f.Format(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "\x1b[31m[err]\x1b[0m") {
+		t.Fatalf("expected colorized level token, got %q", b)
+	}
+
+	f2 := &TextFormatter{ForceColors: true, DisableColors: true}
+	b2, err := f2.Format(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b2), "\x1b[") {
+		t.Fatalf("DisableColors should win over ForceColors, got %q", b2)
+	}
+}
+
+func TestJSONFormatter(t *testing.T) {
+	f := &JSONFormatter{}
+	entry := &Entry{
+		Time:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Level:   LOG_WARNING,
+		Message: "careful",
+		Caller:  "formatter_test.go:1",
+		Fields:  Fields{"retries": 3},
+	}
+	b, err := f.Format(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatalf("Format did not produce valid JSON: %v", err)
+	}
+	if m["msg"] != "careful" || m["level"] != "warning" || m["caller"] != "formatter_test.go:1" {
+		t.Fatalf("unexpected JSON fields: %v", m)
+	}
+	if m["retries"].(float64) != 3 {
+		t.Fatalf("expected bound field to be merged in, got %v", m)
+	}
+}
+
+func TestLogfmtFormatterQuoting(t *testing.T) {
+	f := &LogfmtFormatter{}
+	entry := &Entry{
+		Level:   LOG_INFO,
+		Message: "needs quoting",
+		Fields:  Fields{"plain": 1, "spaced": "has space"},
+	}
+	b, err := f.Format(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(b)
+	if !strings.Contains(got, `msg="needs quoting"`) {
+		t.Fatalf("expected quoted msg, got %q", got)
+	}
+	if !strings.Contains(got, "plain=1") {
+		t.Fatalf("expected unquoted plain value, got %q", got)
+	}
+	if !strings.Contains(got, `spaced="has space"`) {
+		t.Fatalf("expected quoted spaced value, got %q", got)
+	}
+}
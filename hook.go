@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"log"
+	"os"
+	"sync"
+)
+
+// Hook lets an external sink tap every Entry a Logger emits, independently
+// of the Logger's own Formatter/writer. AddHook registers one; at emit time
+// each hook whose Levels() includes the current Level has Fire called with
+// the materialized Entry.
+type Hook interface {
+	Levels() []Level
+	Fire(entry *Entry) error
+}
+
+func levelIn(levels []Level, level Level) bool {
+	for _, l := range levels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// LevelsUpTo returns every Level at least as severe as level (LOG_EMERG
+// through level, inclusive) for use as a Hook's Levels(), e.g.
+// LevelsUpTo(LOG_ERR) for "Error and above".
+func LevelsUpTo(level Level) []Level {
+	levels := make([]Level, 0, level+1)
+	for l := LOG_EMERG; l <= level; l++ {
+		levels = append(levels, l)
+	}
+	return levels
+}
+
+// SyslogHook forwards matching Entries to a local or remote syslogd,
+// independently of the Logger's own output. It reuses the same wire
+// encoding as NewSyslogLogger (see SyslogFormatter) but with its own
+// Facility, since a Hook commonly wants to tag its copy differently than
+// the primary output.
+type SyslogHook struct {
+	Facility  Facility
+	levels    []Level
+	writer    *syslogWriter
+	formatter *SyslogFormatter
+}
+
+// NewSyslogHook dials network/addr (see NewSyslogLogger for the supported
+// networks) and returns a Hook that fires on every Level in levels.
+func NewSyslogHook(network, addr string, facility Facility, tag string, levels []Level) *SyslogHook {
+	return &SyslogHook{
+		Facility:  facility,
+		levels:    levels,
+		writer:    newSyslogWriter(network, addr, FramingLF),
+		formatter: &SyslogFormatter{Tag: tag, WireFormat: Format3164},
+	}
+}
+
+func (h *SyslogHook) Levels() []Level {
+	return h.levels
+}
+
+func (h *SyslogHook) Fire(entry *Entry) error {
+	e := *entry
+	e.Facility = h.Facility
+	b, err := h.formatter.Format(&e)
+	if err != nil {
+		return err
+	}
+	_, err = h.writer.Write(b)
+	return err
+}
+
+// FileHook mirrors matching Entries to a secondary file, e.g. so that
+// Error-and-above records also land in their own file regardless of where
+// the Logger's primary output goes.
+type FileHook struct {
+	levels    []Level
+	formatter Formatter
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileHook opens (creating and appending to) path and returns a Hook
+// that mirrors every Level in levels to it using a TextFormatter.
+func NewFileHook(path string, levels []Level) (*FileHook, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileHook{
+		levels:    levels,
+		formatter: &TextFormatter{Flag: log.LstdFlags},
+		file:      f,
+	}, nil
+}
+
+func (h *FileHook) Levels() []Level {
+	return h.levels
+}
+
+func (h *FileHook) Fire(entry *Entry) error {
+	b, err := h.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err = h.file.Write(b)
+	return err
+}
+
+// Close closes the underlying file.
+func (h *FileHook) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.file.Close()
+}
@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLevelsUpTo(t *testing.T) {
+	got := LevelsUpTo(LOG_ERR)
+	want := []Level{LOG_EMERG, LOG_ALERT, LOG_CRIT, LOG_ERR}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+	for _, l := range want {
+		if !levelIn(got, l) {
+			t.Errorf("levelIn(%v, %v) = false, want true", got, l)
+		}
+	}
+	if levelIn(got, LOG_WARNING) {
+		t.Errorf("levelIn(%v, LOG_WARNING) = true, want false", got)
+	}
+}
+
+func TestHookFiresOnMatchingLevelOnly(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(&buf, "", 0, LOG_DEBUG)
+
+	var fired []Level
+	l.AddHook(&recordingHook{levels: LevelsUpTo(LOG_WARNING), fired: &fired})
+
+	l.Error("err")
+	l.Debug("debug")
+
+	if len(fired) != 1 || fired[0] != LOG_ERR {
+		t.Fatalf("expected hook to fire once for LOG_ERR, got %v", fired)
+	}
+}
+
+func TestHookErrorGoesToErrorHandler(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(&buf, "", 0, LOG_DEBUG)
+
+	wantErr := errors.New("boom")
+	l.AddHook(&failingHook{err: wantErr})
+
+	var gotErr error
+	l.SetErrorHandler(func(err error) { gotErr = err })
+
+	l.Info("msg")
+
+	if gotErr != wantErr {
+		t.Fatalf("expected ErrorHandler to receive %v, got %v", wantErr, gotErr)
+	}
+	if buf.Len() == 0 {
+		t.Fatalf("a failing hook must not block the primary write")
+	}
+}
+
+func TestFileHook(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mirror.log")
+	h, err := NewFileHook(path, LevelsUpTo(LOG_ERR))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	entry := &Entry{Level: LOG_ERR, Message: "disk on fire"}
+	if err := h.Fire(entry); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "disk on fire") {
+		t.Fatalf("expected mirrored message in %s, got %q", path, b)
+	}
+}
+
+type recordingHook struct {
+	levels []Level
+	fired  *[]Level
+}
+
+func (h *recordingHook) Levels() []Level { return h.levels }
+func (h *recordingHook) Fire(entry *Entry) error {
+	*h.fired = append(*h.fired, entry.Level)
+	return nil
+}
+
+type failingHook struct {
+	err error
+}
+
+func (h *failingHook) Levels() []Level { return LevelsUpTo(LOG_DEBUG) }
+func (h *failingHook) Fire(entry *Entry) error {
+	return h.err
+}
@@ -22,7 +22,10 @@ import (
 	"io"
 	"log"
 	"os"
+	"runtime"
 	"strings"
+	"sync"
+	"time"
 )
 
 type Level int
@@ -82,7 +85,7 @@ func LOG_MAKEPRI(fac Facility, pri Level) int {
 
 var Levels = map[Level]string {
 	LOG_ALERT:	"alert",
-	LOG_CRIT:	"crit",	
+	LOG_CRIT:	"crit",
 	LOG_DEBUG:	"debug",
 	LOG_EMERG:	"emerg",
 	LOG_ERR:	"err",
@@ -129,20 +132,62 @@ func LOG_UPTO(pri Level) int {
 	return ((1 << (uint(pri) + 1)) - 1)	// all priorities through pri
 }
 
+// Fields is the set of key/value pairs a Logger created by WithField /
+// WithFields attaches to every Entry it emits.
+type Fields map[string]interface{}
+
+// Entry is the materialized record a Formatter renders into wire bytes.
+// It is built fresh for every log call; Fields and Caller are only
+// populated when applicable (Fields may be nil, Caller is empty unless the
+// Logger's flags ask for file/line information).
+type Entry struct {
+	Time     time.Time
+	Level    Level
+	Facility Facility
+	Message  string
+	Fields   Fields
+	Caller   string
+}
+
+// Formatter renders an Entry into the bytes that get written to a
+// Logger's underlying io.Writer. See TextFormatter, JSONFormatter and
+// LogfmtFormatter for the built-in implementations.
+type Formatter interface {
+	Format(entry *Entry) ([]byte, error)
+}
+
 type Logger struct {
-	logger *log.Logger
-	upto int
+	mu           sync.Mutex
+	out          io.Writer
+	prefix       string
+	flag         int
+	facility     Facility
+	upto         int
+	formatter    Formatter
+	fields       Fields
+	hooks        []Hook
+	errorHandler func(error)
 }
 
 func NewLogger(out io.Writer, prefix string, flag int, priority Level) *Logger {
-	return &Logger{log.New(out, prefix, flag), LOG_UPTO(priority)}
+	return &Logger{
+		out:       out,
+		prefix:    prefix,
+		flag:      flag,
+		upto:      LOG_UPTO(priority),
+		formatter: &TextFormatter{Flag: flag, color: isTerminalWriter(out)},
+	}
 }
 
 func (l *Logger) Flags() int {
-	return l.logger.Flags()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.flag
 }
 func (l *Logger) Prefix() string {
-	return l.logger.Prefix()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.prefix
 }
 func (l *Logger) Priority() Level {
 	// return Level(math.Log2(float64(^l.upto & (l.upto + 1))))
@@ -156,51 +201,215 @@ func (l *Logger) Priority() Level {
 	b = (b & 0x0000ffff) + ((b>>16) & 0x0000ffff) // sum of 16 bit numbers
 	return Level(b)
 }
+// SetFlags sets the output flags, using the same bits as the standard
+// "log" package (Ldate, Ltime, Lshortfile, ...). It also updates the
+// Logger's *TextFormatter, if that is still the configured Formatter, so
+// that existing callers of SetFlags keep working unmodified after
+// switching to a different Formatter via SetFormatter.
 func (l *Logger) SetFlags(flag int) {
-	l.logger.SetFlags(flag)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.flag = flag
+	if tf, ok := l.formatter.(*TextFormatter); ok {
+		tf.Flag = flag
+	}
 }
 func (l *Logger) SetPrefix(prefix string) {
-	l.logger.SetPrefix(prefix)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.prefix = prefix
 }
 func (l *Logger) SetPriority(priority Level) {
 	l.upto = LOG_UPTO(priority)
 }
 
+// SetFormatter replaces the Formatter used to render Entries into bytes.
+func (l *Logger) SetFormatter(formatter Formatter) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.formatter = formatter
+}
+
+// AddHook registers a Hook that fires on every Entry whose Level is in
+// hook.Levels(), after the level mask check and before the Entry is handed
+// to the Formatter.
+func (l *Logger) AddHook(hook Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, hook)
+}
+
+// SetErrorHandler sets the func called with a Hook's error when its Fire
+// returns one. A failing hook never blocks or drops the primary write; if
+// no handler is set, hook errors are silently discarded.
+func (l *Logger) SetErrorHandler(handler func(error)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.errorHandler = handler
+}
+
+// WithField returns a child Logger that attaches key=value to every Entry
+// it emits, in addition to any fields already bound by an ancestor.
+func (l *Logger) WithField(key string, value interface{}) *Logger {
+	return l.WithFields(Fields{key: value})
+}
+
+// WithFields returns a child Logger that attaches fields to every Entry it
+// emits, in addition to any fields already bound by an ancestor. The
+// parent Logger is left untouched.
+func (l *Logger) WithFields(fields Fields) *Logger {
+	l.mu.Lock()
+	child := &Logger{
+		out:          l.out,
+		prefix:       l.prefix,
+		flag:         l.flag,
+		facility:     l.facility,
+		upto:         l.upto,
+		formatter:    l.formatter,
+		fields:       make(Fields, len(l.fields)+len(fields)),
+		hooks:        l.hooks,
+		errorHandler: l.errorHandler,
+	}
+	for k, v := range l.fields {
+		child.fields[k] = v
+	}
+	l.mu.Unlock()
+	for k, v := range fields {
+		child.fields[k] = v
+	}
+	return child
+}
+
 func (l *Logger) Panic(format string, v ...interface{}) {
-	s := fmt.Sprintf("[panic] " + format, v...)
-	l.logger.Output(3, s)
-	panic(s)
+	l.panic(3, format, v...)
 }
 func (l *Logger) Fatal(format string, v ...interface{}) {
-	l.logger.Output(3, fmt.Sprintf("[fatal] " + format, v...))
+	l.fatal(3, format, v...)
+}
+
+func (l *Logger) panic(calldepth int, format string, v ...interface{}) {
+	s := l.withBacktrace(calldepth, fmt.Sprintf("[panic] "+format, v...))
+	l.writeRaw(s)
+	panic(s)
+}
+func (l *Logger) fatal(calldepth int, format string, v ...interface{}) {
+	l.writeRaw(l.withBacktrace(calldepth, fmt.Sprintf("[fatal] "+format, v...)))
 	os.Exit(1)
 }
-func (l *Logger) printf(format string, prio Level, v ...interface{}) {
-	l.logger.Output(3, fmt.Sprintf(fmt.Sprintf("[%s] %s", Levels[prio], format), v...))
+
+// withBacktrace appends a runtime.Stack dump to s when the caller at
+// calldepth matches a location registered via SetBacktraceAt.
+func (l *Logger) withBacktrace(calldepth int, s string) string {
+	if !backtraceEnabled() {
+		return s
+	}
+	_, file, line, ok := runtime.Caller(calldepth)
+	if !ok {
+		return s
+	}
+	if matched, all := backtraceMatch(file, line); matched {
+		return s + "\n" + captureStack(all)
+	}
+	return s
+}
+
+// writeRaw writes a pre-formatted line straight to the underlying writer,
+// bypassing the Formatter. Panic and Fatal use it so a process about to
+// die or unwind gets its message out the same way whichever Formatter is
+// configured. Only the prefix and s are written: no date/time or file:line
+// header is added, whatever the Logger's flags.
+func (l *Logger) writeRaw(s string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	io.WriteString(l.out, l.prefix)
+	io.WriteString(l.out, s)
+	io.WriteString(l.out, "\n")
 }
+
+// output builds an Entry for the current call and dispatches it to the
+// configured Formatter. calldepth is handed to runtime.Caller from within
+// output and selects the frame reported when file/line information is
+// requested: 1 is output's caller, 2 that caller's caller (the
+// application's call site for Info and the other level methods).
+func (l *Logger) output(calldepth int, prio Level, msg string) {
+	entry := &Entry{
+		Time:     time.Now(),
+		Level:    prio,
+		Facility: l.facility,
+		Message:  msg,
+		Fields:   l.fields,
+	}
+	l.mu.Lock()
+	flag := l.flag
+	formatter := l.formatter
+	hooks := l.hooks
+	errorHandler := l.errorHandler
+	l.mu.Unlock()
+
+	wantCaller := flag&(log.Lshortfile|log.Llongfile) != 0
+	if wantCaller || backtraceEnabled() {
+		_, file, line, ok := runtime.Caller(calldepth)
+		if !ok {
+			file, line = "???", 0
+		}
+		if wantCaller {
+			displayFile := file
+			if flag&log.Lshortfile != 0 {
+				if idx := strings.LastIndexByte(displayFile, '/'); idx >= 0 {
+					displayFile = displayFile[idx+1:]
+				}
+			}
+			entry.Caller = fmt.Sprintf("%s:%d", displayFile, line)
+		}
+		if matched, all := backtraceMatch(file, line); matched {
+			entry.Message = entry.Message + "\n" + captureStack(all)
+		}
+	}
+
+	for _, hook := range hooks {
+		if !levelIn(hook.Levels(), prio) {
+			continue
+		}
+		if err := hook.Fire(entry); err != nil && errorHandler != nil {
+			errorHandler(err)
+		}
+	}
+
+	b, err := formatter.Format(entry)
+	if err != nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.prefix != "" {
+		io.WriteString(l.out, l.prefix)
+	}
+	l.out.Write(b)
+}
+
 func (l *Logger) Emerg(format string, v ...interface{}) {
-	if l.upto & LOG_MASK(LOG_EMERG) != 0 { l.printf(format, LOG_EMERG, v...) }
+	if l.upto & LOG_MASK(LOG_EMERG) != 0 { l.output(2, LOG_EMERG, fmt.Sprintf(format, v...)) }
 }
 func (l *Logger) Alert(format string, v ...interface{}) {
-	if l.upto & LOG_MASK(LOG_ALERT) != 0 { l.printf(format, LOG_ALERT, v...) }
+	if l.upto & LOG_MASK(LOG_ALERT) != 0 { l.output(2, LOG_ALERT, fmt.Sprintf(format, v...)) }
 }
 func (l *Logger) Crit(format string, v ...interface{}) {
-	if l.upto & LOG_MASK(LOG_CRIT) != 0 { l.printf(format, LOG_CRIT, v...) }
+	if l.upto & LOG_MASK(LOG_CRIT) != 0 { l.output(2, LOG_CRIT, fmt.Sprintf(format, v...)) }
 }
 func (l *Logger) Error(format string, v ...interface{}) {
-	if l.upto & LOG_MASK(LOG_ERR) != 0 { l.printf(format, LOG_ERR, v...) }
+	if l.upto & LOG_MASK(LOG_ERR) != 0 { l.output(2, LOG_ERR, fmt.Sprintf(format, v...)) }
 }
 func (l *Logger) Warning(format string, v ...interface{}) {
-	if l.upto & LOG_MASK(LOG_WARNING) != 0 { l.printf(format, LOG_WARNING, v...) }
+	if l.upto & LOG_MASK(LOG_WARNING) != 0 { l.output(2, LOG_WARNING, fmt.Sprintf(format, v...)) }
 }
 func (l *Logger) Notice(format string, v ...interface{}) {
-	if l.upto & LOG_MASK(LOG_NOTICE) != 0 { l.printf(format, LOG_NOTICE, v...) }
+	if l.upto & LOG_MASK(LOG_NOTICE) != 0 { l.output(2, LOG_NOTICE, fmt.Sprintf(format, v...)) }
 }
 func (l *Logger) Info(format string, v ...interface{}) {
-	if l.upto & LOG_MASK(LOG_INFO) != 0 { l.printf(format, LOG_INFO, v...) }
+	if l.upto & LOG_MASK(LOG_INFO) != 0 { l.output(2, LOG_INFO, fmt.Sprintf(format, v...)) }
 }
 func (l *Logger) Debug(format string, v ...interface{}) {
-	if l.upto & LOG_MASK(LOG_DEBUG) != 0 {  l.printf(format, LOG_DEBUG, v...) }
+	if l.upto & LOG_MASK(LOG_DEBUG) != 0 { l.output(2, LOG_DEBUG, fmt.Sprintf(format, v...)) }
 }
 
 // function
@@ -212,10 +421,29 @@ func init() {
 			break
 		}
 	}
+	switch strings.ToLower(os.Getenv("GOLOGFORMAT")) {
+	case "json":
+		SetFormatter(&JSONFormatter{})
+	case "logfmt":
+		SetFormatter(&LogfmtFormatter{})
+	}
+}
+
+// SetOutput replaces the Logger's underlying io.Writer in place, leaving
+// its Formatter, Hooks, bound Fields and ErrorHandler untouched. If the
+// current Formatter is a *TextFormatter, its auto-detected terminal state
+// is refreshed against the new writer.
+func (l *Logger) SetOutput(out io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.out = out
+	if tf, ok := l.formatter.(*TextFormatter); ok {
+		tf.color = isTerminalWriter(out)
+	}
 }
 
 func SetOutput(out io.Writer) {
-	std = NewLogger(out, Prefix(), Flags(), Priority())
+	std.SetOutput(out)
 }
 func Flags() int {
 	return std.Flags()
@@ -235,39 +463,51 @@ func SetPrefix(prefix string) {
 func SetPriority(priority Level) {
 	std.SetPriority(priority)
 }
+func SetFormatter(formatter Formatter) {
+	std.SetFormatter(formatter)
+}
+func WithField(key string, value interface{}) *Logger {
+	return std.WithField(key, value)
+}
+func WithFields(fields Fields) *Logger {
+	return std.WithFields(fields)
+}
+func AddHook(hook Hook) {
+	std.AddHook(hook)
+}
+func SetErrorHandler(handler func(error)) {
+	std.SetErrorHandler(handler)
+}
 
 var std = NewLogger(os.Stderr, "", log.LstdFlags, LOG_ERR)
 
 func Panic(format string, v ...interface{}) {
-	s := fmt.Sprintf("[panic] " + format, v...)
-	std.logger.Output(3, s)
-	panic(s)
+	std.panic(3, format, v...)
 }
 func Fatal(format string, v ...interface{}) {
-	std.logger.Output(3, fmt.Sprintf("[fatal] " + format, v...))
-	os.Exit(1)
+	std.fatal(3, format, v...)
 }
 func Emerg(format string, v ...interface{}) {
-	if std.upto & LOG_MASK(LOG_EMERG) != 0 { std.printf(format, LOG_EMERG, v...) }
+	if std.upto & LOG_MASK(LOG_EMERG) != 0 { std.output(2, LOG_EMERG, fmt.Sprintf(format, v...)) }
 }
 func Alert(format string, v ...interface{}) {
-	if std.upto & LOG_MASK(LOG_ALERT) != 0 { std.printf(format, LOG_ALERT, v...) }
+	if std.upto & LOG_MASK(LOG_ALERT) != 0 { std.output(2, LOG_ALERT, fmt.Sprintf(format, v...)) }
 }
 func Crit(format string, v ...interface{}) {
-	if std.upto & LOG_MASK(LOG_CRIT) != 0 { std.printf(format, LOG_CRIT, v...) }
+	if std.upto & LOG_MASK(LOG_CRIT) != 0 { std.output(2, LOG_CRIT, fmt.Sprintf(format, v...)) }
 }
 func Error(format string, v ...interface{}) {
-	if std.upto & LOG_MASK(LOG_ERR) != 0 { std.printf(format, LOG_ERR, v...) }
+	if std.upto & LOG_MASK(LOG_ERR) != 0 { std.output(2, LOG_ERR, fmt.Sprintf(format, v...)) }
 }
 func Warning(format string, v ...interface{}) {
-	if std.upto & LOG_MASK(LOG_WARNING) != 0 { std.printf(format, LOG_WARNING, v...) }
+	if std.upto & LOG_MASK(LOG_WARNING) != 0 { std.output(2, LOG_WARNING, fmt.Sprintf(format, v...)) }
 }
 func Notice(format string, v ...interface{}) {
-	if std.upto & LOG_MASK(LOG_NOTICE) != 0 { std.printf(format, LOG_NOTICE, v...) }
+	if std.upto & LOG_MASK(LOG_NOTICE) != 0 { std.output(2, LOG_NOTICE, fmt.Sprintf(format, v...)) }
 }
 func Info(format string, v ...interface{}) {
-	if std.upto & LOG_MASK(LOG_INFO) != 0 { std.printf(format, LOG_INFO, v...) }
+	if std.upto & LOG_MASK(LOG_INFO) != 0 { std.output(2, LOG_INFO, fmt.Sprintf(format, v...)) }
 }
 func Debug(format string, v ...interface{}) {
-	if std.upto & LOG_MASK(LOG_DEBUG) != 0 {  std.printf(format, LOG_DEBUG, v...) }
+	if std.upto & LOG_MASK(LOG_DEBUG) != 0 { std.output(2, LOG_DEBUG, fmt.Sprintf(format, v...)) }
 }
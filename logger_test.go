@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWithFieldsMerge(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(&buf, "", 0, LOG_DEBUG)
+	l.SetFormatter(&LogfmtFormatter{})
+
+	child := l.WithField("a", 1).WithField("b", 2)
+	child.Info("msg")
+
+	got := buf.String()
+	if !strings.Contains(got, "a=1") || !strings.Contains(got, "b=2") {
+		t.Fatalf("expected both ancestor fields present, got %q", got)
+	}
+
+	buf.Reset()
+	l.Info("parent unaffected")
+	if strings.Contains(buf.String(), "a=1") {
+		t.Fatalf("WithField must not mutate the parent Logger, got %q", buf.String())
+	}
+}
+
+func TestSetOutputPreservesConfig(t *testing.T) {
+	var first, second bytes.Buffer
+	l := NewLogger(&first, "", 0, LOG_DEBUG)
+	l.SetFormatter(&JSONFormatter{})
+
+	var fired int
+	l.AddHook(&countingHook{count: &fired})
+
+	l.SetOutput(&second)
+	l.Info("hello")
+
+	if first.Len() != 0 {
+		t.Fatalf("expected nothing written to the old writer, got %q", first.String())
+	}
+	if !strings.Contains(second.String(), `"msg":"hello"`) {
+		t.Fatalf("expected JSONFormatter to still be in effect, got %q", second.String())
+	}
+	if fired != 1 {
+		t.Fatalf("expected the hook registered before SetOutput to still fire, got %d", fired)
+	}
+}
+
+type countingHook struct {
+	count *int
+}
+
+func (h *countingHook) Levels() []Level { return LevelsUpTo(LOG_DEBUG) }
+func (h *countingHook) Fire(entry *Entry) error {
+	*h.count++
+	return nil
+}
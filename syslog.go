@@ -0,0 +1,167 @@
+package logger
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// Framing selects how a syslog message is delimited on a stream (TCP)
+// transport. Datagram transports (UDP, unixgram) ignore it entirely since
+// one packet is one message.
+type Framing int
+
+const (
+	FramingLF		= Framing(0)	// non-transparent framing: message, then LF (the traditional behaviour)
+	FramingOctetCounted	= Framing(1)	// RFC 6587 octet-counted framing: "MSGLEN SP MSG"
+)
+
+// SyslogFormat selects the wire message format a SyslogFormatter produces.
+type SyslogFormat int
+
+const (
+	Format3164	= SyslogFormat(0)	// RFC 3164 (BSD syslog)
+	Format5424	= SyslogFormat(1)	// RFC 5424
+)
+
+// SyslogFormatter renders an Entry as a framed syslog message, computing
+// the <PRI> part from the Entry's Facility and Level via LOG_MAKEPRI. It is
+// the Formatter installed on Loggers returned by NewSyslogLogger /
+// NewSyslogLoggerRFC5424.
+type SyslogFormatter struct {
+	Tag        string
+	WireFormat SyslogFormat
+	Hostname   string // auto-detected via os.Hostname() when empty
+
+	pidOnce sync.Once
+	pid     int
+}
+
+func (f *SyslogFormatter) Format(entry *Entry) ([]byte, error) {
+	hostname := f.Hostname
+	if hostname == "" {
+		if h, err := os.Hostname(); err == nil {
+			hostname = h
+		} else {
+			hostname = "-"
+		}
+	}
+	f.pidOnce.Do(func() { f.pid = os.Getpid() })
+	pri := LOG_MAKEPRI(entry.Facility, entry.Level)
+
+	switch f.WireFormat {
+	case Format5424:
+		return []byte(fmt.Sprintf("<%d>1 %s %s %s %d - - %s",
+			pri, entry.Time.UTC().Format(time.RFC3339), hostname, f.Tag, f.pid, entry.Message)), nil
+	default:
+		return []byte(fmt.Sprintf("<%d>%s %s %s[%d]: %s",
+			pri, entry.Time.Format(time.Stamp), hostname, f.Tag, f.pid, entry.Message)), nil
+	}
+}
+
+// syslogWriter is the transport half of syslog output: it dials network/
+// addr when created, redials on the next Write after a failure (so a restarting
+// syslogd does not require the caller to recreate the Logger), and applies
+// TCP framing. The actual RFC 3164/5424 message construction lives in
+// SyslogFormatter, upstream of this writer in the pipeline.
+type syslogWriter struct {
+	network string
+	addr    string
+	framing Framing
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func newSyslogWriter(network, addr string, framing Framing) *syslogWriter {
+	w := &syslogWriter{network: network, addr: addr, framing: framing}
+	w.connect()
+	return w
+}
+
+func (w *syslogWriter) connect() error {
+	conn, err := net.Dial(w.network, w.addr)
+	if err != nil {
+		w.conn = nil
+		return err
+	}
+	w.conn = conn
+	return nil
+}
+
+func (w *syslogWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		if err := w.connect(); err != nil {
+			return 0, err
+		}
+	}
+
+	var out []byte
+	switch {
+	case w.network == "tcp" && w.framing == FramingOctetCounted:
+		out = []byte(fmt.Sprintf("%d %s", len(p), p))
+	case w.network == "udp" || w.network == "unixgram":
+		// One packet is one message on a datagram transport; there is no
+		// framing to apply and no following message to separate from.
+		out = p
+	default:
+		out = append(append(out, p...), '\n')
+	}
+
+	n, err := w.conn.Write(out)
+	if n > len(p) {
+		// Framing overhead (octet-count prefix, trailing LF) must not leak
+		// into the io.Writer contract that n <= len(p).
+		n = len(p)
+	}
+	if err != nil {
+		w.conn.Close()
+		w.conn = nil
+	}
+	return n, err
+}
+
+// NewSyslogLogger returns a *Logger that frames every message as an RFC
+// 3164 syslog packet (the default) and ships it to addr over network
+// ("udp", "tcp" or "unix"). The connection is dialed when the Logger is
+// created; if that dial or a later write fails, it is redialed on the next
+// write.
+//
+// For "tcp", use SetSyslogFraming on the returned Logger to switch to RFC
+// 6587 octet-counted framing; the default is non-transparent framing (one
+// message per line).
+func NewSyslogLogger(network, addr string, facility Facility, tag string, priority Level) *Logger {
+	return &Logger{
+		out:       newSyslogWriter(network, addr, FramingLF),
+		facility:  facility,
+		upto:      LOG_UPTO(priority),
+		formatter: &SyslogFormatter{Tag: tag, WireFormat: Format3164},
+	}
+}
+
+// NewSyslogLoggerRFC5424 is like NewSyslogLogger but frames messages per
+// RFC 5424 instead of the BSD RFC 3164 format.
+func NewSyslogLoggerRFC5424(network, addr string, facility Facility, tag string, priority Level) *Logger {
+	return &Logger{
+		out:       newSyslogWriter(network, addr, FramingLF),
+		facility:  facility,
+		upto:      LOG_UPTO(priority),
+		formatter: &SyslogFormatter{Tag: tag, WireFormat: Format5424},
+	}
+}
+
+// SetSyslogFraming changes the TCP framing mode of a Logger created by
+// NewSyslogLogger / NewSyslogLoggerRFC5424. It is a no-op for Loggers whose
+// writer is not a syslog writer.
+func (l *Logger) SetSyslogFraming(framing Framing) {
+	if w, ok := l.out.(*syslogWriter); ok {
+		w.mu.Lock()
+		w.framing = framing
+		w.mu.Unlock()
+	}
+}
@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslogFormatter3164(t *testing.T) {
+	f := &SyslogFormatter{Tag: "myapp", WireFormat: Format3164, Hostname: "host1"}
+	entry := &Entry{
+		Time:     time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Level:    LOG_ERR,
+		Facility: LOG_USER,
+		Message:  "boom",
+	}
+	b, err := f.Format(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(b)
+	wantPRI := fmt.Sprintf("<%d>", LOG_MAKEPRI(LOG_USER, LOG_ERR))
+	if !strings.HasPrefix(got, wantPRI) {
+		t.Fatalf("expected PRI %s, got: %s", wantPRI, got)
+	}
+	if !strings.Contains(got, "host1") || !strings.Contains(got, "myapp[") || !strings.HasSuffix(got, "boom") {
+		t.Fatalf("missing expected RFC 3164 fields: %s", got)
+	}
+}
+
+func TestSyslogFormatter5424(t *testing.T) {
+	f := &SyslogFormatter{Tag: "myapp", WireFormat: Format5424, Hostname: "host1"}
+	entry := &Entry{Time: time.Now(), Level: LOG_INFO, Facility: LOG_LOCAL0, Message: "hi"}
+	b, err := f.Format(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(b)
+	wantPRI := fmt.Sprintf("<%d>1 ", LOG_MAKEPRI(LOG_LOCAL0, LOG_INFO))
+	if !strings.HasPrefix(got, wantPRI) {
+		t.Fatalf("expected RFC 5424 PRI+version %s, got: %s", wantPRI, got)
+	}
+	if !strings.Contains(got, "host1") || !strings.Contains(got, "myapp") || !strings.HasSuffix(got, "hi") {
+		t.Fatalf("missing expected RFC 5424 fields: %s", got)
+	}
+}
+
+func TestSyslogUDPTransport(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+
+	l := NewSyslogLogger("udp", pc.LocalAddr().String(), LOG_USER, "testtag", LOG_DEBUG)
+	l.Error("hello")
+
+	pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, 1024)
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("no syslog datagram received: %v", err)
+	}
+	if !strings.Contains(string(buf[:n]), "hello") {
+		t.Fatalf("unexpected datagram: %s", buf[:n])
+	}
+	if strings.HasSuffix(string(buf[:n]), "\n") {
+		t.Fatalf("a datagram is one message; it must not carry a trailing LF: %q", buf[:n])
+	}
+}
+
+func TestSyslogWriteReturnsLenP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+
+	w := newSyslogWriter("udp", pc.LocalAddr().String(), FramingLF)
+	defer func() {
+		w.mu.Lock()
+		if w.conn != nil {
+			w.conn.Close()
+		}
+		w.mu.Unlock()
+	}()
+
+	p := []byte("payload")
+	n, err := w.Write(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(p) {
+		t.Fatalf("Write returned n=%d, want %d (io.Writer requires n <= len(p))", n, len(p))
+	}
+}
+
+func TestSyslogTCPOctetFraming(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	connCh := make(chan net.Conn, 1)
+	go func() {
+		c, _ := ln.Accept()
+		connCh <- c
+	}()
+
+	l := NewSyslogLogger("tcp", ln.Addr().String(), LOG_USER, "tag", LOG_DEBUG)
+	l.SetSyslogFraming(FramingOctetCounted)
+
+	conn := <-connCh
+	defer conn.Close()
+	l.Error("framed")
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, 1024)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("no data: %v", err)
+	}
+	got := string(buf[:n])
+
+	sp := strings.IndexByte(got, ' ')
+	if sp <= 0 {
+		t.Fatalf("missing octet-count prefix: %q", got)
+	}
+	count, err := strconv.Atoi(got[:sp])
+	if err != nil || count != len(got[sp+1:]) {
+		t.Fatalf("octet count %d doesn't match message length %d: %q", count, len(got[sp+1:]), got)
+	}
+}
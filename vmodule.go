@@ -0,0 +1,135 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type vmoduleRule struct {
+	pattern string
+	level   Level
+}
+
+var (
+	vmoduleMu    sync.RWMutex
+	vmoduleRules []vmoduleRule
+	vmoduleCache sync.Map // uintptr (caller PC) -> Level
+)
+
+func init() {
+	if spec := os.Getenv("GOLOGVMODULE"); spec != "" {
+		SetVModule(spec)
+	}
+}
+
+// SetVModule parses a glog-style "pattern=level,pattern=level" spec and
+// installs it as the set of per-file verbosity overrides consulted by V().
+// Each pattern is matched with filepath.Match's "*" and "?" globs, against
+// the caller's file basename (e.g. "bar.go=4") unless the pattern itself
+// contains a "/", in which case it is matched against the whole path that
+// runtime.Caller reports, usually absolute (e.g. "*/foo/*=7"), so whole
+// directories can be enabled at once.
+func SetVModule(spec string) {
+	var rules []vmoduleRule
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		n, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+		rules = append(rules, vmoduleRule{pattern: parts[0], level: Level(n)})
+	}
+	vmoduleMu.Lock()
+	vmoduleRules = rules
+	vmoduleMu.Unlock()
+	// Every cached PC was resolved against the old rule set. Clear entries
+	// in place rather than reassigning vmoduleCache itself, since V() reads
+	// and writes it without holding vmoduleMu.
+	vmoduleCache.Range(func(k, _ interface{}) bool {
+		vmoduleCache.Delete(k)
+		return true
+	})
+}
+
+// vmoduleMaxLevel returns the highest V level enabled for file by the
+// current vmodule rules, or -1 if no rule matches it.
+func vmoduleMaxLevel(file string) Level {
+	vmoduleMu.RLock()
+	defer vmoduleMu.RUnlock()
+
+	base := filepath.Base(file)
+	max := Level(-1)
+	for _, rule := range vmoduleRules {
+		target := base
+		if strings.Contains(rule.pattern, "/") {
+			target = file
+		}
+		if ok, _ := filepath.Match(rule.pattern, target); ok && rule.level > max {
+			max = rule.level
+		}
+	}
+	return max
+}
+
+// Verbose is the gate V() returns. Its methods are no-ops when the gate is
+// closed, so call sites can be left in production code at no cost.
+type Verbose bool
+
+func (v Verbose) Info(args ...interface{}) {
+	if v {
+		std.output(2, LOG_INFO, fmt.Sprint(args...))
+	}
+}
+func (v Verbose) Infof(format string, args ...interface{}) {
+	if v {
+		std.output(2, LOG_INFO, fmt.Sprintf(format, args...))
+	}
+}
+func (v Verbose) Debug(args ...interface{}) {
+	if v {
+		std.output(2, LOG_DEBUG, fmt.Sprint(args...))
+	}
+}
+func (v Verbose) Debugf(format string, args ...interface{}) {
+	if v {
+		std.output(2, LOG_DEBUG, fmt.Sprintf(format, args...))
+	}
+}
+
+// V reports whether level-gated logging is enabled for the caller: it is
+// enabled if either the package's global level already allows level, or
+// SetVModule / GOLOGVMODULE enables it for the caller's file. The match is
+// cached per caller PC (invalidated by SetVModule) so a repeat call from
+// the same site costs a runtime.Caller lookup, a map lookup and an integer
+// compare rather than a pattern match.
+func V(level Level) Verbose {
+	if std.upto&LOG_MASK(level) != 0 {
+		return Verbose(true)
+	}
+
+	pc, file, _, ok := runtime.Caller(1)
+	if !ok {
+		return Verbose(false)
+	}
+
+	var max Level
+	if cached, hit := vmoduleCache.Load(pc); hit {
+		max = cached.(Level)
+	} else {
+		max = vmoduleMaxLevel(file)
+		vmoduleCache.Store(pc, max)
+	}
+	return Verbose(level <= max)
+}
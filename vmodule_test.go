@@ -0,0 +1,77 @@
+package logger
+
+import "testing"
+
+func TestVModuleBasenameMatch(t *testing.T) {
+	SetVModule("foo.go=3,bar_*.go=5")
+	defer SetVModule("")
+
+	cases := []struct {
+		file string
+		want Level
+	}{
+		{"/src/pkg/foo.go", 3},
+		{"/src/pkg/bar_test.go", 5},
+		{"/src/pkg/baz.go", -1},
+	}
+	for _, c := range cases {
+		if got := vmoduleMaxLevel(c.file); got != c.want {
+			t.Errorf("vmoduleMaxLevel(%q) = %v, want %v", c.file, got, c.want)
+		}
+	}
+}
+
+func TestVModuleFullPathMatch(t *testing.T) {
+	SetVModule("pkg/sub/*=7")
+	defer SetVModule("")
+
+	if got := vmoduleMaxLevel("pkg/sub/file.go"); got != 7 {
+		t.Errorf("vmoduleMaxLevel(pkg/sub/file.go) = %v, want 7", got)
+	}
+	if got := vmoduleMaxLevel("pkg/other/file.go"); got != -1 {
+		t.Errorf("vmoduleMaxLevel(pkg/other/file.go) = %v, want -1", got)
+	}
+}
+
+func TestVModuleHighestRuleWins(t *testing.T) {
+	SetVModule("*.go=2,foo.go=9")
+	defer SetVModule("")
+
+	if got := vmoduleMaxLevel("foo.go"); got != 9 {
+		t.Errorf("vmoduleMaxLevel(foo.go) = %v, want 9 (highest matching rule)", got)
+	}
+	if got := vmoduleMaxLevel("bar.go"); got != 2 {
+		t.Errorf("vmoduleMaxLevel(bar.go) = %v, want 2", got)
+	}
+}
+
+func TestVModuleInvalidEntriesIgnored(t *testing.T) {
+	SetVModule("foo.go=notanumber,=5,bar.go,baz.go=4")
+	defer SetVModule("")
+
+	if got := vmoduleMaxLevel("foo.go"); got != -1 {
+		t.Errorf("malformed level should be skipped, got %v", got)
+	}
+	if got := vmoduleMaxLevel("baz.go"); got != 4 {
+		t.Errorf("vmoduleMaxLevel(baz.go) = %v, want 4", got)
+	}
+}
+
+// checkVDebug is called from two different vmodule states so that both
+// calls share the same caller PC, exercising V()'s per-call-site cache.
+func checkVDebug() bool {
+	return bool(V(LOG_DEBUG))
+}
+
+func TestSetVModuleInvalidatesCache(t *testing.T) {
+	// std's default priority (LOG_ERR) doesn't already gate LOG_DEBUG open,
+	// so this exercises the vmodule path rather than the global level check.
+	SetVModule("vmodule_test.go=7")
+	if !checkVDebug() {
+		t.Fatalf("expected V(LOG_DEBUG) to be enabled under vmodule_test.go=7")
+	}
+	SetVModule("")
+	if checkVDebug() {
+		t.Fatalf("expected SetVModule(\"\") to invalidate the cached result for this call site")
+	}
+}